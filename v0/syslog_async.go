@@ -0,0 +1,138 @@
+package mlog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// defaultAsyncQueueSize is used when SyslogProxyOptions.Async is set but
+// AsyncQueueSize is left at its zero value.
+const defaultAsyncQueueSize = 256
+
+// startAsyncWorkers launches n goroutines draining s.asyncCh. n is usually 1,
+// which preserves the order lines were queued in.
+func (s *SyslogProxy) startAsyncWorkers(n int) {
+	s.asyncWG.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer s.asyncWG.Done()
+			for payload := range s.asyncCh {
+				s.deliver(payload)
+			}
+		}()
+	}
+}
+
+// acquireConnForWrite returns the connection to write to - (re)connecting
+// first if necessary - along with the deadline and octet-framing flag to use,
+// without holding s.mu for the write itself: a slow/blocked conn.Write must
+// not stall SyslogHandler.Handle, which takes the same mutex to append to
+// s.buf. ok is false if no connection could be established; octetFraming is
+// still returned in that case (best-effort, using the last known value) so
+// the caller can still frame a payload consistently before buffering it.
+func (s *SyslogProxy) acquireConnForWrite() (conn net.Conn, deadline time.Duration, octetFraming bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.IsConnected() {
+		if err := s.reconnect(); err != nil {
+			return nil, 0, s.octetFraming, false
+		}
+	}
+	deadline = s.writeDeadline
+	if deadline <= 0 {
+		deadline = s.timeout
+	}
+	return s.conn, deadline, s.octetFraming, true
+}
+
+// bufferUndelivered keeps an already-framed payload that could not be
+// delivered in the replay buffer, if one is configured, instead of dropping it.
+func (s *SyslogProxy) bufferUndelivered(payload []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ring != nil {
+		s.ring.push(payload)
+	}
+}
+
+// deliver writes a single queued, not-yet-framed payload, (re)connecting
+// first if necessary and retrying once on a failed write, mirroring
+// writeWithRetry. If delivery still fails and a replay buffer is configured,
+// the payload is buffered instead of being dropped.
+//
+// Unlike writeWithRetry, deliver only holds s.mu long enough to read/refresh
+// the connection (acquireConnForWrite/bufferUndelivered) - the conn.Write
+// itself runs unlocked, so a stuck syslog receiver blocks only this worker,
+// not every goroutine calling SyslogHandler.Handle.
+func (s *SyslogProxy) deliver(payload []byte) {
+	conn, deadline, octetFraming, ok := s.acquireConnForWrite()
+	framed := frameForWrite(payload, octetFraming)
+	if !ok {
+		s.bufferUndelivered(framed)
+		return
+	}
+
+	if err := conn.SetWriteDeadline(time.Now().Add(deadline)); err == nil {
+		if _, err := conn.Write(framed); err == nil {
+			return
+		}
+	}
+
+	// single retry after a reconnect, mirroring writeWithRetry: drop the
+	// failed connection (unless another worker already replaced it) so
+	// acquireConnForWrite re-dials. octetFraming may have changed (e.g. s.url
+	// now names a tls:// target), so re-frame.
+	s.mu.Lock()
+	if s.conn == conn {
+		s.Disconnect()
+	}
+	s.mu.Unlock()
+	conn, deadline, octetFraming, ok = s.acquireConnForWrite()
+	framed = frameForWrite(payload, octetFraming)
+	if !ok {
+		s.bufferUndelivered(framed)
+		return
+	}
+	if err := conn.SetWriteDeadline(time.Now().Add(deadline)); err != nil {
+		s.bufferUndelivered(framed)
+		return
+	}
+	if _, err := conn.Write(framed); err != nil {
+		s.bufferUndelivered(framed)
+	}
+}
+
+// Close gracefully shuts down the proxy: if Async is enabled it stops
+// accepting new lines and waits for the queue to drain, then stops the
+// background reconnect/drain goroutine (if a replay buffer is configured)
+// and finally flushes any lines still pending - all bounded by ctx.
+// Close is not safe to call concurrently with ProcessLines/Handle.
+func (s *SyslogProxy) Close(ctx context.Context) error {
+	if s.async {
+		close(s.asyncCh)
+		done := make(chan struct{})
+		go func() {
+			s.asyncWG.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: async queue still draining: %w", ErrSyslogProcessHandleResult, ctx.Err())
+		}
+	}
+
+	if s.drainStop != nil {
+		close(s.drainStop)
+		select {
+		case <-s.drainDone:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrSyslogProcessHandleResult, ctx.Err())
+		}
+	}
+
+	return s.Flush(ctx)
+}
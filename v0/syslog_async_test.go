@@ -0,0 +1,106 @@
+package mlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConn is a minimal net.Conn that records every Write, used to verify
+// that the async worker delivers lines in the order they were queued.
+type fakeConn struct {
+	mu      sync.Mutex
+	written [][]byte
+}
+
+func (f *fakeConn) Read([]byte) (int, error) { return 0, fmt.Errorf("fakeConn: Read not supported") }
+
+func (f *fakeConn) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written = append(f.written, append([]byte(nil), b...))
+	return len(b), nil
+}
+
+func (f *fakeConn) Close() error                    { return nil }
+func (f *fakeConn) LocalAddr() net.Addr             { return nil }
+func (f *fakeConn) RemoteAddr() net.Addr            { return nil }
+func (f *fakeConn) SetDeadline(time.Time) error     { return nil }
+func (f *fakeConn) SetReadDeadline(time.Time) error { return nil }
+func (f *fakeConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+func (f *fakeConn) snapshot() [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]byte(nil), f.written...)
+}
+
+// hangingConn.Write never returns, modelling a syslog receiver that stopped
+// acking writes (e.g. a dead TCP peer the kernel hasn't noticed yet).
+type hangingConn struct {
+	fakeConn
+}
+
+func (h *hangingConn) Write([]byte) (int, error) {
+	select {}
+}
+
+func newTestAsyncProxy(conn net.Conn) *SyslogProxy {
+	s := &SyslogProxy{
+		buf:     bytes.NewBuffer(nil),
+		mu:      &sync.Mutex{},
+		conn:    conn,
+		url:     "tcp://example.invalid:514",
+		timeout: time.Second,
+		async:   true,
+	}
+	s.asyncCh = make(chan []byte, 16)
+	s.startAsyncWorkers(1)
+	return s
+}
+
+func TestAsyncProcessLinesPreservesOrderAndClose(t *testing.T) {
+	conn := &fakeConn{}
+	s := newTestAsyncProxy(conn)
+	identity := func(line []byte) ([]byte, error) { return line, nil }
+
+	for _, line := range []string{"line1", "line2", "line3"} {
+		fmt.Fprintln(s.buf, line)
+		require.NoError(t, s.ProcessLines(identity))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, s.Close(ctx))
+
+	written := conn.snapshot()
+	require.Len(t, written, 3)
+	assert.Equal(t, "line1\n", string(written[0]))
+	assert.Equal(t, "line2\n", string(written[1]))
+	assert.Equal(t, "line3\n", string(written[2]))
+}
+
+// TestAsyncCloseRespectsDeadlineWhenWorkerIsStuck documents a known
+// limitation (see the review of the Block overflow policy): Close bounds how
+// long the *caller* waits, but it cannot force an in-flight conn.Write to
+// return, so a worker genuinely stuck in a syscall stays stuck. What Close
+// guarantees is that it gives up and reports that rather than hanging itself.
+func TestAsyncCloseRespectsDeadlineWhenWorkerIsStuck(t *testing.T) {
+	s := newTestAsyncProxy(&hangingConn{})
+	fmt.Fprintln(s.buf, "never delivered")
+	require.NoError(t, s.ProcessLines(func(line []byte) ([]byte, error) { return line, nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := s.Close(ctx)
+	assert.Error(t, err)
+}
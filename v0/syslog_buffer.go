@@ -0,0 +1,133 @@
+package mlog
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultReconnectInterval = 5 * time.Second
+	flushRetryInterval       = 100 * time.Millisecond
+)
+
+// maxBlockWait/blockPollInterval bound the Block overflow policy: a waiter
+// re-checks maxBlockWait every blockPollInterval and, once it has waited that
+// long, degrades to DropOldest instead of parking forever. Without this, a
+// persistently unreachable syslog server would livelock the (usually single)
+// async worker in ringBuffer.push, and once the async queue also filled up,
+// block every caller of SyslogHandler.Handle too - defeating the point of
+// Async/Block in the first place. Declared as vars, not consts, so tests can
+// shrink them.
+var (
+	maxBlockWait      = 5 * time.Second        //nolint:gochecknoglobals
+	blockPollInterval = 100 * time.Millisecond //nolint:gochecknoglobals
+)
+
+// OverflowPolicy controls what happens once the SyslogProxy replay buffer
+// (filled while the connection is down, or a write failed) is full.
+type OverflowPolicy int
+
+const (
+	// DropOldest discards the oldest buffered line to make room for the new one.
+	DropOldest OverflowPolicy = iota
+	// DropNewest discards the incoming line, keeping what is already buffered.
+	DropNewest
+	// Block waits, releasing SyslogProxy's mutex, until the background drain
+	// goroutine (or a caller of Flush) frees up space. If nothing frees space
+	// within maxBlockWait, it degrades to DropOldest rather than waiting
+	// forever - a persistently unreachable server would otherwise livelock
+	// the draining goroutine (and, in Async mode, eventually Handle itself).
+	Block
+)
+
+// ringBuffer is a bounded FIFO of formatted syslog lines, used by SyslogProxy
+// to replay log records once a dropped connection is re-established. All
+// methods assume the owning SyslogProxy's mutex is held by the caller.
+type ringBuffer struct {
+	lines      [][]byte
+	maxLines   int
+	maxBytes   int
+	bytes      int
+	policy     OverflowPolicy
+	spaceAvail *sync.Cond
+}
+
+// newRingBuffer returns a ringBuffer bounded by maxLines and/or maxBytes
+// (either may be 0 to mean "no limit on that dimension"). mu is the
+// SyslogProxy mutex guarding the buffer; it backs the Cond used by the
+// Block overflow policy.
+func newRingBuffer(maxLines, maxBytes int, policy OverflowPolicy, mu sync.Locker) *ringBuffer {
+	return &ringBuffer{
+		maxLines:   maxLines,
+		maxBytes:   maxBytes,
+		policy:     policy,
+		spaceAvail: sync.NewCond(mu),
+	}
+}
+
+// push appends line to the buffer, applying the configured OverflowPolicy if
+// it is full. Caller must hold the owning SyslogProxy's mutex.
+func (b *ringBuffer) push(line []byte) {
+	var blockDeadline time.Time
+	for b.full(len(line)) {
+		switch b.policy {
+		case DropOldest:
+			b.popLocked()
+		case DropNewest:
+			return
+		case Block:
+			if blockDeadline.IsZero() {
+				blockDeadline = time.Now().Add(maxBlockWait)
+			}
+			if time.Now().After(blockDeadline) {
+				b.popLocked() // give up waiting, see the Block doc comment
+				continue
+			}
+			b.waitTimeout(blockPollInterval)
+		}
+	}
+	b.lines = append(b.lines, line)
+	b.bytes += len(line)
+}
+
+// waitTimeout waits on spaceAvail for at most timeout, so a Block-policy
+// waiter periodically re-checks its deadline instead of parking forever.
+func (b *ringBuffer) waitTimeout(timeout time.Duration) {
+	timer := time.AfterFunc(timeout, func() {
+		b.spaceAvail.L.Lock()
+		b.spaceAvail.Broadcast()
+		b.spaceAvail.L.Unlock()
+	})
+	defer timer.Stop()
+	b.spaceAvail.Wait()
+}
+
+func (b *ringBuffer) full(incoming int) bool {
+	if len(b.lines) == 0 {
+		return false // always accept at least one line, even if it alone exceeds maxBytes
+	}
+	if b.maxLines > 0 && len(b.lines) >= b.maxLines {
+		return true
+	}
+	return b.maxBytes > 0 && b.bytes+incoming > b.maxBytes
+}
+
+// peek returns the oldest buffered line without removing it.
+func (b *ringBuffer) peek() []byte {
+	return b.lines[0]
+}
+
+// popLocked removes the oldest buffered line. Caller must hold the owning
+// SyslogProxy's mutex.
+func (b *ringBuffer) popLocked() {
+	if len(b.lines) == 0 {
+		return
+	}
+	b.bytes -= len(b.lines[0])
+	b.lines = b.lines[1:]
+	b.spaceAvail.Broadcast()
+}
+
+func (b *ringBuffer) len() int {
+	return len(b.lines)
+}
@@ -0,0 +1,99 @@
+package mlog
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBufferDropOldest(t *testing.T) {
+	var mu sync.Mutex
+	b := newRingBuffer(2, 0, DropOldest, &mu)
+
+	b.push([]byte("a"))
+	b.push([]byte("b"))
+	b.push([]byte("c")) // buffer full: "a" should be evicted
+
+	require.Equal(t, 2, b.len())
+	assert.Equal(t, []byte("b"), b.peek())
+}
+
+func TestRingBufferDropNewest(t *testing.T) {
+	var mu sync.Mutex
+	b := newRingBuffer(2, 0, DropNewest, &mu)
+
+	b.push([]byte("a"))
+	b.push([]byte("b"))
+	b.push([]byte("c")) // buffer full: "c" should be discarded
+
+	require.Equal(t, 2, b.len())
+	assert.Equal(t, []byte("a"), b.peek())
+}
+
+func TestRingBufferMaxBytes(t *testing.T) {
+	var mu sync.Mutex
+	b := newRingBuffer(0, 5, DropOldest, &mu)
+
+	b.push([]byte("abc")) // 3 bytes
+	b.push([]byte("de"))  // 5 bytes total, still fits
+	require.Equal(t, 2, b.len())
+
+	b.push([]byte("f")) // would exceed maxBytes: evict "abc" first
+	require.Equal(t, 2, b.len())
+	assert.Equal(t, []byte("de"), b.peek())
+}
+
+func TestRingBufferBlockWaitsThenProceeds(t *testing.T) {
+	var mu sync.Mutex
+	b := newRingBuffer(1, 0, Block, &mu)
+	b.push([]byte("a"))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		b.popLocked()
+		mu.Unlock()
+	}()
+
+	mu.Lock()
+	start := time.Now()
+	b.push([]byte("b")) // should block until the goroutine above pops "a"
+	elapsed := time.Since(start)
+	mu.Unlock()
+
+	assert.GreaterOrEqual(t, elapsed, 15*time.Millisecond)
+	require.Equal(t, 1, b.len())
+	assert.Equal(t, []byte("b"), b.peek())
+}
+
+func TestRingBufferBlockDegradesToDropOldestAfterTimeout(t *testing.T) {
+	origWait, origPoll := maxBlockWait, blockPollInterval
+	maxBlockWait = 30 * time.Millisecond
+	blockPollInterval = 5 * time.Millisecond
+	defer func() { maxBlockWait, blockPollInterval = origWait, origPoll }()
+
+	var mu sync.Mutex
+	b := newRingBuffer(1, 0, Block, &mu)
+	b.push([]byte("a"))
+
+	done := make(chan struct{})
+	mu.Lock()
+	go func() {
+		b.push([]byte("b")) // nobody ever pops "a": must degrade, not hang forever
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		mu.Unlock()
+		t.Fatal("push with Block policy did not degrade to DropOldest in time")
+	}
+	mu.Unlock()
+
+	require.Equal(t, 1, b.len())
+	assert.Equal(t, []byte("b"), b.peek())
+}
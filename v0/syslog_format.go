@@ -0,0 +1,73 @@
+package mlog
+
+import (
+	"fmt"
+	"log/slog"
+	logSyslog "log/syslog"
+	"os"
+	"time"
+)
+
+// SyslogFormat selects the header (if any) that [SyslogProxy.ProcessLines]
+// prepends to every outgoing line.
+type SyslogFormat int
+
+const (
+	// FormatRaw forwards the line exactly as produced by the wrapped slog.Handler.
+	FormatRaw SyslogFormat = iota
+	// FormatRFC3164 prepends a classic BSD syslog header: `<PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]:`.
+	FormatRFC3164
+	// FormatRFC5424 prepends a structured syslog header:
+	// `<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [STRUCTURED-DATA] MSG`.
+	FormatRFC5424
+)
+
+const (
+	rfc3164TimeLayout = "Jan _2 15:04:05"
+	rfc5424TimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+	utf8BOM           = "\uFEFF"
+)
+
+// DefaultLevelToSeverity maps the usual slog levels to the closest [log/syslog] severity.
+func DefaultLevelToSeverity(level slog.Level) logSyslog.Priority {
+	switch {
+	case level >= slog.LevelError:
+		return logSyslog.LOG_ERR
+	case level >= slog.LevelWarn:
+		return logSyslog.LOG_WARNING
+	case level >= slog.LevelInfo:
+		return logSyslog.LOG_INFO
+	default:
+		return logSyslog.LOG_DEBUG
+	}
+}
+
+// frameLine prepends the PRI header, and for FormatRFC3164/FormatRFC5424 the
+// rest of the RFC header, required by a conformant syslog receiver. msg is
+// the already-processed payload (e.g. with the timestamp trimmed by the caller).
+func (s *SyslogProxy) frameLine(format SyslogFormat, severity logSyslog.Priority, msg []byte) ([]byte, error) {
+	if format == FormatRaw {
+		return msg, nil
+	}
+
+	pri := (int(s.priority) & facilityMask) | (int(severity) & severityMask)
+
+	now := time.Now()
+	if !s.useLocalTZ {
+		now = now.UTC()
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	switch format {
+	case FormatRFC3164:
+		return fmt.Appendf(nil, "<%d>%s %s %s[%d]: %s", pri, now.Format(rfc3164TimeLayout), hostname, s.tag, os.Getpid(), msg), nil
+	case FormatRFC5424:
+		return fmt.Appendf(nil, "<%d>1 %s %s %s %d - - %s%s", pri, now.Format(rfc5424TimeLayout), hostname, s.tag, os.Getpid(), utf8BOM, msg), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown syslog format %d", ErrSyslogFormat, format)
+	}
+}
@@ -0,0 +1,78 @@
+package mlog
+
+import (
+	"log/slog"
+	logSyslog "log/syslog"
+	"os"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultLevelToSeverity(t *testing.T) {
+	cases := []struct {
+		level slog.Level
+		want  logSyslog.Priority
+	}{
+		{slog.LevelDebug, logSyslog.LOG_DEBUG},
+		{slog.LevelInfo, logSyslog.LOG_INFO},
+		{slog.LevelWarn, logSyslog.LOG_WARNING},
+		{slog.LevelError, logSyslog.LOG_ERR},
+		{slog.LevelError + 4, logSyslog.LOG_ERR}, // anything above Error still maps to LOG_ERR
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, DefaultLevelToSeverity(c.level), "level %v", c.level)
+	}
+}
+
+func TestFrameLineRaw(t *testing.T) {
+	s := &SyslogProxy{priority: logSyslog.LOG_USER | logSyslog.LOG_INFO, tag: "myapp"}
+
+	got, err := s.frameLine(FormatRaw, logSyslog.LOG_ERR, []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("hello"), got)
+}
+
+func TestFrameLineRFC3164(t *testing.T) {
+	s := &SyslogProxy{priority: logSyslog.LOG_LOCAL0 | logSyslog.LOG_INFO, tag: "myapp", useLocalTZ: false}
+
+	got, err := s.frameLine(FormatRFC3164, logSyslog.LOG_ERR, []byte("boom"))
+	require.NoError(t, err)
+
+	wantPRI := (int(logSyslog.LOG_LOCAL0) & facilityMask) | (int(logSyslog.LOG_ERR) & severityMask)
+	hostname, _ := os.Hostname()
+	pid := os.Getpid()
+
+	re := regexp.MustCompile(
+		`^<` + regexp.QuoteMeta(strconv.Itoa(wantPRI)) + `>\w{3}\s+\d{1,2} \d{2}:\d{2}:\d{2} ` +
+			regexp.QuoteMeta(hostname) + ` myapp\[` + regexp.QuoteMeta(strconv.Itoa(pid)) + `\]: boom$`,
+	)
+	assert.Regexp(t, re, string(got))
+}
+
+func TestFrameLineRFC5424(t *testing.T) {
+	s := &SyslogProxy{priority: logSyslog.LOG_LOCAL0 | logSyslog.LOG_INFO, tag: "myapp", useLocalTZ: false}
+
+	got, err := s.frameLine(FormatRFC5424, logSyslog.LOG_WARNING, []byte("boom"))
+	require.NoError(t, err)
+
+	wantPRI := (int(logSyslog.LOG_LOCAL0) & facilityMask) | (int(logSyslog.LOG_WARNING) & severityMask)
+	hostname, _ := os.Hostname()
+	pid := os.Getpid()
+
+	re := regexp.MustCompile(
+		`^<` + regexp.QuoteMeta(strconv.Itoa(wantPRI)) + `>1 \S+ ` + regexp.QuoteMeta(hostname) +
+			` myapp ` + regexp.QuoteMeta(strconv.Itoa(pid)) + ` - - ` + regexp.QuoteMeta(utf8BOM) + `boom$`,
+	)
+	assert.Regexp(t, re, string(got))
+}
+
+func TestFrameLineUnknownFormat(t *testing.T) {
+	s := &SyslogProxy{}
+	_, err := s.frameLine(SyslogFormat(99), logSyslog.LOG_INFO, []byte("x"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSyslogFormat)
+}
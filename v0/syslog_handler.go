@@ -19,6 +19,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log/slog"
@@ -36,7 +37,7 @@ const (
 	facilityMask = 0xf8 // see log/syslog code
 )
 
-var allowedProto = []string{"tcp", "udp", "unix"} //nolint:gochecknoglobals
+var allowedProto = []string{"tcp", "udp", "unix", "unixgram", "tls", "tcp+tls"} //nolint:gochecknoglobals
 
 // -----------------------------------------------------------------------------
 // SyslogProxy is a type that provides interaction with the syslog Proxyserver
@@ -49,9 +50,24 @@ type SyslogProxy struct {
 	url          string
 	conn         net.Conn // connection, disconnected if nil
 	useLocalTZ   bool
-	timeout      time.Duration
-	stderrLogger *slog.Logger
-	mu           *sync.Mutex
+	octetFraming bool // effective value for the current connection; see octetFramingOpt
+	// octetFramingOpt is the value configured via SyslogProxyOptions.OctetFraming.
+	// Connect/reconnect derive octetFraming from it (forcing it on for TLS, per
+	// RFC 5425) so a later re-Connect to a plain-text URL doesn't leave
+	// octet-counting stuck on from an earlier TLS session.
+	octetFramingOpt bool
+	timeout         time.Duration
+	stderrLogger    *slog.Logger
+	mu              *sync.Mutex
+	ring            *ringBuffer   // nil if replay buffering is disabled
+	drainStop       chan struct{} // closed to stop the background reconnect/drain goroutine
+	drainDone       chan struct{} // closed once the background goroutine has returned
+
+	async         bool
+	asyncCh       chan []byte // nil unless Async is enabled
+	asyncWG       sync.WaitGroup
+	writeDeadline time.Duration // deadline used for async writes; falls back to timeout when 0
+	tlsConfig     *tls.Config   // used for the "tls"/"tcp+tls" schemes; nil means system roots + URL host
 }
 
 // Writer returns a [io.Writer] which may be used
@@ -78,6 +94,8 @@ func (s *SyslogProxy) Unlock() {
 //	tcp://1.2.3.4:514
 //	udp://1.2.3.4:514
 //	unix:///var/run/syslog
+//	unixgram:///dev/log
+//	tls://logs.example.com:6514
 //
 // if timeout is 0 the default timeout will be used
 func (s *SyslogProxy) Connect(url string, timeout time.Duration) error {
@@ -97,11 +115,16 @@ func (s *SyslogProxy) Connect(url string, timeout time.Duration) error {
 		return fmt.Errorf("%w: URL `%s` is wrong: unsupported proto '%s', allowed only %v", ErrSyslogURLparse, url, u.Scheme, allowedProto)
 	}
 	proto = u.Scheme
-	if proto == "unix" {
+	if proto == "unix" || proto == "unixgram" {
 		addr = u.Path
 	} else {
 		addr = u.Host
 	}
+
+	// Record the URL before dialing, not after: a failed Connect must still
+	// leave s.url pointing at the URL that was attempted, so a later
+	// Connect("", timeout) (or reconnect, triggered by ProcessLines/Handle)
+	// retries that same target instead of failing for want of a URL.
 	s.url = fmt.Sprintf("%s://%s", proto, addr)
 
 	if timeout == 0 {
@@ -109,7 +132,7 @@ func (s *SyslogProxy) Connect(url string, timeout time.Duration) error {
 	}
 
 	// dial to the Syslog server
-	c, err := net.DialTimeout(proto, addr, timeout)
+	c, err := s.dialProto(proto, addr, timeout)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrSyslogConnection, err)
 	}
@@ -120,6 +143,71 @@ func (s *SyslogProxy) Connect(url string, timeout time.Duration) error {
 	s.Disconnect()
 	s.conn = c
 	s.timeout = timeout
+	s.octetFraming = s.octetFramingOpt || isTLSProto(proto) // RFC 5425 requires octet-counted framing
+	return nil
+}
+
+// dialProto dials addr using the transport named by proto, which is one of
+// allowedProto. TLS/tcp+tls use crypto/tls with s.tlsConfig (or a config
+// built from the system roots and the URL host, if nil); every other proto
+// is dialed with a plain net.DialTimeout.
+func (s *SyslogProxy) dialProto(proto, addr string, timeout time.Duration) (net.Conn, error) {
+	if !isTLSProto(proto) {
+		return net.DialTimeout(proto, addr, timeout)
+	}
+
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	cfg := s.tlsConfig
+	if cfg == nil {
+		cfg = &tls.Config{} //nolint:gosec // system roots are used when RootCAs is nil; ServerName is set below
+	} else {
+		cfg = cfg.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = host
+	}
+	return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, cfg)
+}
+
+func isTLSProto(proto string) bool {
+	return proto == "tls" || proto == "tcp+tls"
+}
+
+// reconnect closes the current connection (if any) and re-dials the previously
+// used s.url, honouring s.timeout. It is used both to lazily establish a
+// deferred connection and to recover from a write failure.
+// Caller must already hold s.mu (or otherwise guarantee exclusive access).
+func (s *SyslogProxy) reconnect() error {
+	if s.url == "" {
+		return fmt.Errorf("%w: not connected", ErrSyslogConnection)
+	}
+
+	u, err := netURL.Parse(s.url)
+	if err != nil {
+		return fmt.Errorf("%w: URL `%s` is wrong: %w", ErrSyslogURLparse, s.url, err)
+	}
+	addr := u.Host
+	if u.Scheme == "unix" || u.Scheme == "unixgram" {
+		addr = u.Path
+	}
+
+	timeout := s.timeout
+	if timeout == 0 {
+		timeout = defaultDialTimeout
+	}
+
+	c, err := s.dialProto(u.Scheme, addr, timeout)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSyslogConnection, err)
+	}
+
+	s.Disconnect()
+	s.conn = c
+	s.timeout = timeout
+	s.octetFraming = s.octetFramingOpt || isTLSProto(u.Scheme)
 	return nil
 }
 
@@ -136,34 +224,73 @@ func (s *SyslogProxy) Disconnect() {
 
 // ProcessLines process each line of LocalBuffer by given function.
 // be carefully, strongly recommended wrap this call by mutex Lock()/Unlock.
+//
+// If the proxy is not connected yet (deferred mode) or a write fails, it is
+// dialed/re-dialed once using the previously stored URL and timeout; the
+// failing write is then retried a single time before the error is surfaced,
+// mirroring the behaviour of [log/syslog]'s Writer.
 func (s *SyslogProxy) ProcessLines(processFunc func([]byte) ([]byte, error)) (err error) {
-	if !s.IsConnected() {
-		return fmt.Errorf("%w: not connected", ErrSyslogConnection)
-	}
-	if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
-		return fmt.Errorf("%w: %w", ErrSyslogConnection, err)
+	var connected bool
+	if !s.async {
+		connected = s.IsConnected()
+		if !connected {
+			connected = s.reconnect() == nil
+		}
+		switch {
+		case connected:
+			if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+				return fmt.Errorf("%w: %w", ErrSyslogConnection, err)
+			}
+		case s.ring == nil:
+			// Nothing downstream will ever read s.buf's current contents: the
+			// wrapped handler already wrote into it before this call (see
+			// SyslogHandler.Handle), and without a replay buffer there is
+			// nowhere to keep it. Drop it now rather than letting it grow
+			// unbounded on every Handle call while disconnected, followed by
+			// a burst replay once reconnected.
+			s.buf.Reset()
+			return fmt.Errorf("%w: not connected", ErrSyslogConnection)
+		}
 	}
 
-	// todo(sv): Should be rewriten for async usage !!!
-	// all processing should have ability to execute in separated goroutine, i.e. threadsafe
 	scanner := bufio.NewScanner(s.buf)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if line != "" {
-			line, err := processFunc([]byte(line))
+			payload, err := processFunc([]byte(line))
 			if err != nil {
 				return err // just return err from user provided function
 			}
-			_, err = s.conn.Write(line)
-			if err != nil {
-				return fmt.Errorf("%w: %w", ErrSyslogWrite, err)
+
+			if s.async {
+				// Framing (octet-count prefix vs trailing EOL) depends on
+				// s.octetFraming, which for a deferred connection is only known
+				// once the worker actually dials - see deliver in
+				// syslog_async.go. Applying it here, at enqueue time, would use
+				// a stale pre-connect value and could corrupt the framing of a
+				// TLS (RFC 5425) stream. The actual conn.Write also happens on
+				// that dedicated worker goroutine, keeping network latency off
+				// the calling slog.Logger method.
+				s.asyncCh <- payload
+				continue
 			}
-			if line[len(line)-1] != '\n' { // add EOL if not present after processing by user function
-				_, err = s.conn.Write([]byte("\n")) // each line should leads by \n it is a Syslog protocol requirements
-				if err != nil {
-					return fmt.Errorf("%w: %w", ErrSyslogWrite, err)
+
+			payload = frameForWrite(payload, s.octetFraming)
+
+			if connected {
+				wErr := s.writeWithRetry(payload)
+				if wErr == nil {
+					continue
+				}
+				connected = false
+				if s.ring == nil {
+					return wErr
 				}
 			}
+			// connection is down and a replay buffer is configured: keep the
+			// record instead of dropping it, it will be flushed once
+			// the background goroutine (or an explicit Flush) reconnects.
+			s.ring.push(payload)
 		}
 	}
 	if err := scanner.Err(); err != nil {
@@ -172,12 +299,166 @@ func (s *SyslogProxy) ProcessLines(processFunc func([]byte) ([]byte, error)) (er
 	return nil
 }
 
+// Pending returns the number of lines currently held in the replay buffer,
+// waiting for the connection to be re-established. It is always 0 when no
+// buffer was configured via SyslogProxyOptions.BufferSize/BufferBytes.
+func (s *SyslogProxy) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ring == nil {
+		return 0
+	}
+	return s.ring.len()
+}
+
+// Flush attempts to (re)connect and drain the replay buffer, blocking until
+// it is empty or ctx is done. It is meant to be called during graceful
+// shutdown so no buffered record is silently dropped.
+func (s *SyslogProxy) Flush(ctx context.Context) error {
+	if s.ring == nil {
+		return nil
+	}
+	for {
+		s.mu.Lock()
+		s.drainLocked()
+		pending := s.ring.len()
+		s.mu.Unlock()
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %d line(s) still pending: %w", ErrSyslogProcessHandleResult, pending, ctx.Err())
+		case <-time.After(flushRetryInterval):
+		}
+	}
+}
+
+// drainLocked (re)connects if necessary and writes out buffered lines in
+// order. Caller must hold s.mu.
+func (s *SyslogProxy) drainLocked() {
+	if s.ring == nil || s.ring.len() == 0 {
+		return
+	}
+	if !s.IsConnected() {
+		if err := s.reconnect(); err != nil {
+			return
+		}
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+		return
+	}
+	for s.ring.len() > 0 {
+		if err := s.writeWithRetry(s.ring.peek()); err != nil {
+			return
+		}
+		s.ring.popLocked()
+	}
+}
+
+// startDrainLoop runs a background goroutine that periodically calls
+// drainLocked, so a replay buffer filled while the server was unreachable
+// gets flushed even if no further log record ever arrives.
+func (s *SyslogProxy) startDrainLoop(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReconnectInterval
+	}
+	s.drainStop = make(chan struct{})
+	s.drainDone = make(chan struct{})
+	go func() {
+		defer close(s.drainDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.drainStop:
+				return
+			case <-ticker.C:
+				s.mu.Lock()
+				s.drainLocked()
+				s.mu.Unlock()
+			}
+		}
+	}()
+}
+
+// frameForWrite applies the wire framing ProcessLines used to apply inline:
+// RFC 6587 octet-counting (when octetFraming is set, required by RFC 5425
+// over TLS) or, otherwise, a trailing EOL if processFunc's output doesn't
+// already end in one.
+func frameForWrite(payload []byte, octetFraming bool) []byte {
+	if octetFraming {
+		return append(fmt.Appendf(nil, "%d ", len(payload)), payload...)
+	}
+	if len(payload) == 0 || payload[len(payload)-1] != '\n' {
+		return append(payload, '\n')
+	}
+	return payload
+}
+
+// writeWithRetry writes b to the current connection. On failure it
+// reconnects to the previously used URL once and retries the write,
+// only returning ErrSyslogWrite if the retry also fails.
+func (s *SyslogProxy) writeWithRetry(b []byte) error {
+	if _, err := s.conn.Write(b); err != nil {
+		if rErr := s.reconnect(); rErr != nil {
+			return fmt.Errorf("%w: %w", ErrSyslogWrite, err)
+		}
+		if err := s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil {
+			return fmt.Errorf("%w: %w", ErrSyslogConnection, err)
+		}
+		if _, err := s.conn.Write(b); err != nil {
+			return fmt.Errorf("%w: %w", ErrSyslogWrite, err)
+		}
+	}
+	return nil
+}
+
 type SyslogProxyOptions struct {
 	UseLocalTZ         bool
 	Priority           logSyslog.Priority
 	Tag                string
 	IOBufSize          int
 	LineProcessBufSize int
+	// URL, if set, seeds the target used by a deferred (re)connect: ProcessLines
+	// and reconnect will dial it lazily on the first write, so Connect does not
+	// have to be called (or succeed) up front. Same format as Connect's url
+	// argument. Any later Connect call overwrites it, even one whose dial fails.
+	URL string
+	// OctetFraming enables RFC 6587 octet-counting framing ("LENGTH SP MSG")
+	// instead of the traditional trailing-LF framing. Most collectors listening
+	// over TCP accept either, but octet-counting is required by some of them.
+	OctetFraming bool
+	// BufferSize is the maximum number of lines retained in the in-memory
+	// replay buffer while the connection is down or a write fails. 0 (default)
+	// means no line limit; buffering is disabled only when BufferBytes is 0 as
+	// well, in which case a write failure is surfaced to the caller as before.
+	BufferSize int
+	// BufferBytes caps the replay buffer by total payload size, in bytes. 0 means no byte limit.
+	BufferBytes int
+	// OverflowPolicy controls what happens once the replay buffer is full.
+	OverflowPolicy OverflowPolicy
+	// ReconnectInterval sets how often the background goroutine retries the
+	// connection and drains the replay buffer. Only used when BufferSize or
+	// BufferBytes is set. 0 means defaultReconnectInterval.
+	ReconnectInterval time.Duration
+	// Async, if true, makes ProcessLines hand formatted lines off to a
+	// channel-fed queue instead of writing them to conn synchronously; a
+	// background worker performs the actual write. The synchronous behaviour
+	// remains the default for backward compatibility.
+	Async bool
+	// AsyncQueueSize is the channel buffer size used in Async mode. 0 means defaultAsyncQueueSize.
+	AsyncQueueSize int
+	// AsyncWorkers is the number of goroutines draining the async queue.
+	// Defaults to 1, which is what almost all callers want: it preserves the
+	// order in which lines were queued.
+	AsyncWorkers int
+	// WriteDeadline overrides the deadline used for writes performed by the
+	// async worker(s). 0 means fall back to the proxy's dial timeout.
+	WriteDeadline time.Duration
+	// TLSConfig is used when Connect is called with a "tls"/"tcp+tls" URL.
+	// nil means use the system root CAs, with ServerName taken from the URL host.
+	TLSConfig *tls.Config
 }
 
 // NewSyslog setup and return [Syslog] entity.
@@ -208,11 +489,35 @@ func NewSyslogProxy(opts *SyslogProxyOptions) *SyslogProxy {
 		buf:            bytes.NewBuffer(buf),
 		lineProcessBuf: make([]byte, opts.LineProcessBufSize),
 		// hostname:       hostname,
-		priority:     opts.Priority,
-		tag:          opts.Tag,
-		useLocalTZ:   opts.UseLocalTZ,
-		stderrLogger: slog.New(NewHumanReadableHandler(os.Stderr, nil)),
-		mu:           &sync.Mutex{},
+		priority:        opts.Priority,
+		tag:             opts.Tag,
+		useLocalTZ:      opts.UseLocalTZ,
+		url:             opts.URL,
+		octetFraming:    opts.OctetFraming,
+		octetFramingOpt: opts.OctetFraming,
+		stderrLogger:    slog.New(NewHumanReadableHandler(os.Stderr, nil)),
+		mu:              &sync.Mutex{},
+		async:           opts.Async,
+		writeDeadline:   opts.WriteDeadline,
+		tlsConfig:       opts.TLSConfig,
+	}
+
+	if opts.BufferSize > 0 || opts.BufferBytes > 0 {
+		s.ring = newRingBuffer(opts.BufferSize, opts.BufferBytes, opts.OverflowPolicy, s.mu)
+		s.startDrainLoop(opts.ReconnectInterval)
+	}
+
+	if s.async {
+		queueSize := opts.AsyncQueueSize
+		if queueSize <= 0 {
+			queueSize = defaultAsyncQueueSize
+		}
+		workers := opts.AsyncWorkers
+		if workers <= 0 {
+			workers = 1
+		}
+		s.asyncCh = make(chan []byte, queueSize)
+		s.startAsyncWorkers(workers)
 	}
 
 	return s
@@ -220,11 +525,16 @@ func NewSyslogProxy(opts *SyslogProxyOptions) *SyslogProxy {
 
 //-----------------------------------------------------------------------------
 
-// SyslogHandler currently has no options,
-// but this will change in the future and the type is reserved
-// to maintain backward compatibility
+// SyslogHandlerOptions configures a [SyslogHandler].
 type SyslogHandlerOptions struct {
 	LineProcessFunc func(line []byte) ([]byte, error)
+	// Format selects the wire framing applied to every line before it is handed
+	// to [SyslogProxy.ProcessLines]. Defaults to FormatRaw, i.e. the line is
+	// forwarded as produced by LineProcessFunc, unchanged (current behaviour).
+	Format SyslogFormat
+	// LevelToSeverity derives the per-record [log/syslog] severity used by
+	// FormatRFC3164/FormatRFC5424 framing. Defaults to DefaultLevelToSeverity.
+	LevelToSeverity func(slog.Level) logSyslog.Priority
 }
 
 // SyslogHandler is a proxy Handler that ensures
@@ -235,6 +545,8 @@ type SyslogHandler struct {
 	handler         slog.Handler
 	level           slog.Level // should not be set manually. collected from uplevel slog handler
 	lineProcessFunc func(line []byte) ([]byte, error)
+	format          SyslogFormat
+	levelToSeverity func(slog.Level) logSyslog.Priority
 }
 
 func (h *SyslogHandler) Copy() *SyslogHandler {
@@ -243,6 +555,8 @@ func (h *SyslogHandler) Copy() *SyslogHandler {
 		handler:         h.handler,
 		level:           h.level,
 		lineProcessFunc: h.lineProcessFunc,
+		format:          h.format,
+		levelToSeverity: h.levelToSeverity,
 	}
 	return rv
 }
@@ -278,21 +592,36 @@ func (h *SyslogHandler) WithGroup(name string) slog.Handler {
 // It will only be called when Enabled(...) returns true.
 // Implements [slog.Handler] interface.
 func (h *SyslogHandler) Handle(ctx context.Context, r slog.Record) error { //nolint:gocritic
-	if !h.syslogPx.IsConnected() {
-		return fmt.Errorf("%w: not connected", ErrSyslogConnection)
-	}
-
 	h.syslogPx.Lock() // should be locked before call chield's Handle() because Writer used by one
 	defer h.syslogPx.Unlock()
 	if err := h.handler.Handle(ctx, r); err != nil {
 		return fmt.Errorf("%w: %w", ErrSyslogHandle, err)
 	}
 
-	err := h.syslogPx.ProcessLines(h.lineProcessFunc)
+	processFunc := h.lineProcessFunc
+	if h.format != FormatRaw {
+		severity := h.levelToSeverity(r.Level)
+		processFunc = func(line []byte) ([]byte, error) {
+			msg, err := h.lineProcessFunc(line)
+			if err != nil {
+				return nil, err
+			}
+			return h.syslogPx.frameLine(h.format, severity, msg)
+		}
+	}
+
+	err := h.syslogPx.ProcessLines(processFunc)
 
 	return err
 }
 
+// Close gracefully shuts down the underlying SyslogProxy: it drains any
+// pending async writes and buffered (not yet delivered) lines before ctx is
+// done. See [SyslogProxy.Close].
+func (h *SyslogHandler) Close(ctx context.Context) error {
+	return h.syslogPx.Close(ctx)
+}
+
 func NewSyslogHandler(syslogPx *SyslogProxy, h slog.Handler, opts *SyslogHandlerOptions) *SyslogHandler {
 	if opts == nil {
 		opts = &SyslogHandlerOptions{}
@@ -303,10 +632,15 @@ func NewSyslogHandler(syslogPx *SyslogProxy, h slog.Handler, opts *SyslogHandler
 			return rv, nil // `unable to trim timestamp` is not a global error
 		}
 	}
+	if opts.LevelToSeverity == nil {
+		opts.LevelToSeverity = DefaultLevelToSeverity
+	}
 	sh := &SyslogHandler{
 		syslogPx:        syslogPx,
 		handler:         h,
 		lineProcessFunc: opts.LineProcessFunc,
+		format:          opts.Format,
+		levelToSeverity: opts.LevelToSeverity,
 	}
 	for _, logLevel := range allowedLevels {
 		if h.Enabled(context.TODO(), logLevel) {
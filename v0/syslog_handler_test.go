@@ -0,0 +1,206 @@
+package mlog
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEchoListener accepts TCP connections on 127.0.0.1 and forwards every
+// byte it reads to the returned channel, for tests that need to observe what
+// a SyslogProxy actually wrote to a real socket.
+func startEchoListener(t *testing.T) (addr string, received chan []byte, cleanup func()) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ch := make(chan []byte, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				buf := make([]byte, 4096)
+				for {
+					n, err := c.Read(buf)
+					if n > 0 {
+						ch <- append([]byte(nil), buf[:n]...)
+					}
+					if err != nil {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String(), ch, func() { ln.Close() }
+}
+
+func TestConnectTCP(t *testing.T) {
+	addr, received, cleanup := startEchoListener(t)
+	defer cleanup()
+
+	s := NewSyslogProxy(nil)
+	require.NoError(t, s.Connect("tcp://"+addr, time.Second))
+	assert.True(t, s.IsConnected())
+	assert.Equal(t, "tcp://"+addr, s.url)
+
+	_, err := s.conn.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "hello\n", string(got))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the echo listener to receive data")
+	}
+}
+
+func TestConnectUnix(t *testing.T) {
+	sockPath := t.TempDir() + "/syslog.sock"
+	ln, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		buf := make([]byte, 64)
+		n, _ := conn.Read(buf)
+		received <- append([]byte(nil), buf[:n]...)
+	}()
+
+	s := NewSyslogProxy(nil)
+	require.NoError(t, s.Connect("unix://"+sockPath, time.Second))
+
+	_, err = s.conn.Write([]byte("hi"))
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "hi", string(got))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the unix listener to receive data")
+	}
+}
+
+func TestConnectUnsupportedProto(t *testing.T) {
+	s := NewSyslogProxy(nil)
+	err := s.Connect("carrier-pigeon://example.invalid", time.Second)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSyslogURLparse)
+}
+
+func TestConnectFailureStillRecordsURLForRetry(t *testing.T) {
+	// Nothing is listening on this port, so the dial fails - but a later
+	// reconnect should still retry the same remembered URL, failing with a
+	// connection error rather than a URL error.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close()) // free the port, nothing will accept on it
+
+	s := NewSyslogProxy(nil)
+	err = s.Connect("tcp://"+addr, 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Equal(t, "tcp://"+addr, s.url)
+
+	err = s.reconnect()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSyslogConnection)
+	assert.NotContains(t, err.Error(), "unsupported proto")
+}
+
+func TestReconnectWithoutURLFails(t *testing.T) {
+	s := &SyslogProxy{mu: &sync.Mutex{}}
+	err := s.reconnect()
+	assert.ErrorIs(t, err, ErrSyslogConnection)
+}
+
+// failingConn is a fakeConn whose Write always fails, used to exercise
+// writeWithRetry's reconnect-and-retry-once path.
+type failingConn struct{ fakeConn }
+
+func (f *failingConn) Write([]byte) (int, error) {
+	return 0, errors.New("simulated write failure")
+}
+
+func TestWriteWithRetryReconnectsOnce(t *testing.T) {
+	addr, received, cleanup := startEchoListener(t)
+	defer cleanup()
+
+	s := NewSyslogProxy(nil)
+	require.NoError(t, s.Connect("tcp://"+addr, time.Second))
+
+	s.mu.Lock()
+	s.conn = &failingConn{} // simulate a broken connection without closing the listener
+	s.mu.Unlock()
+
+	err := s.writeWithRetry([]byte("retry-me\n"))
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "retry-me\n", string(got))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the retried write to reach the listener")
+	}
+}
+
+func TestWriteWithRetryFailsWhenReconnectFails(t *testing.T) {
+	s := &SyslogProxy{mu: &sync.Mutex{}, conn: &failingConn{}, timeout: time.Second}
+	err := s.writeWithRetry([]byte("x"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrSyslogWrite)
+}
+
+// TestProcessLinesDrainsBufWhenNotConnectedAndNoRing is a regression test for
+// the unbounded s.buf growth described in the review of chunk0-1: with no
+// ring buffer configured, a disconnected ProcessLines call must not leave the
+// just-written line sitting in s.buf for the next call to pile on top of.
+func TestProcessLinesDrainsBufWhenNotConnectedAndNoRing(t *testing.T) {
+	s := NewSyslogProxy(nil) // no BufferSize/BufferBytes: no ring buffer
+	identity := func(line []byte) ([]byte, error) { return line, nil }
+
+	for i := 0; i < 3; i++ {
+		s.buf.WriteString("a line nobody will ever read\n")
+		err := s.ProcessLines(identity)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSyslogConnection)
+	}
+
+	assert.Equal(t, 0, s.buf.Len(), "s.buf must not accumulate lines while disconnected with no replay buffer")
+}
+
+// TestDeferredConnectViaURLOption is a regression test for the "deferred
+// mode" doc comment on ProcessLines: seeding SyslogProxyOptions.URL must let
+// the proxy dial lazily on first use, without ever calling Connect.
+func TestDeferredConnectViaURLOption(t *testing.T) {
+	addr, received, cleanup := startEchoListener(t)
+	defer cleanup()
+
+	s := NewSyslogProxy(&SyslogProxyOptions{URL: "tcp://" + addr})
+	assert.False(t, s.IsConnected())
+
+	identity := func(line []byte) ([]byte, error) { return line, nil }
+	s.buf.WriteString("deferred hello\n")
+	require.NoError(t, s.ProcessLines(identity))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "deferred hello\n", string(got))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the deferred connection's write to reach the listener")
+	}
+}
@@ -0,0 +1,103 @@
+package mlog
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTLSProto(t *testing.T) {
+	assert.True(t, isTLSProto("tls"))
+	assert.True(t, isTLSProto("tcp+tls"))
+	assert.False(t, isTLSProto("tcp"))
+	assert.False(t, isTLSProto("unixgram"))
+}
+
+// generateSelfSignedCert returns a throwaway certificate/key pair valid for
+// 127.0.0.1, for tests that need a real tls.Listener without relying on any
+// externally provisioned certificate.
+func generateSelfSignedCert(t *testing.T) tls.Certificate {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &priv.PublicKey, priv)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestConnectTLSEnablesOctetFraming(t *testing.T) {
+	cert := generateSelfSignedCert(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}}) //nolint:gosec // self-signed test cert
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		buf := make([]byte, 64)
+		n, _ := conn.Read(buf)
+		received <- append([]byte(nil), buf[:n]...)
+	}()
+
+	s := NewSyslogProxy(&SyslogProxyOptions{TLSConfig: &tls.Config{InsecureSkipVerify: true}}) //nolint:gosec // self-signed test cert
+	require.NoError(t, s.Connect("tls://"+ln.Addr().String(), time.Second))
+	assert.True(t, s.octetFraming, "Connect over tls:// must force octet framing, per RFC 5425")
+
+	_, err = s.conn.Write([]byte("5 hello"))
+	require.NoError(t, err)
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "5 hello", string(got))
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the TLS listener to receive data")
+	}
+}
+
+func TestConnectUnixgram(t *testing.T) {
+	sockPath := t.TempDir() + "/syslog.sock"
+	pc, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	require.NoError(t, err)
+	defer pc.Close()
+
+	s := NewSyslogProxy(nil)
+	require.NoError(t, s.Connect("unixgram://"+sockPath, time.Second))
+
+	_, err = s.conn.Write([]byte("hi"))
+	require.NoError(t, err)
+
+	require.NoError(t, pc.SetReadDeadline(time.Now().Add(time.Second)))
+	buf := make([]byte, 16)
+	n, err := pc.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hi", string(buf[:n]))
+}